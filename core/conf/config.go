@@ -0,0 +1,685 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const jsonTagName = "json"
+
+type options struct {
+	env     bool
+	flagSet flagLookuper
+}
+
+// Option customizes the way Load/LoadConfig parses a config file.
+type Option func(opt *options)
+
+// UseEnv lets Load/LoadConfig expand ${VAR} (and $VAR) placeholders in the
+// raw file content against the process environment before the content is
+// parsed.
+func UseEnv() Option {
+	return func(opt *options) {
+		opt.env = true
+	}
+}
+
+var loaders = map[string]func([]byte, any) error{
+	".json": LoadFromJsonBytes,
+	".yaml": LoadFromYamlBytes,
+	".yml":  LoadFromYamlBytes,
+	".toml": LoadFromTomlBytes,
+}
+
+// parsers mirrors loaders but stops short of decoding into a struct, so Load
+// can thread a decodeContext (flag fallbacks) through to field resolution.
+var parsers = map[string]func([]byte) (any, error){
+	".json": parseJsonBytes,
+	".yaml": parseYamlBytes,
+	".yml":  parseYamlBytes,
+	".toml": parseTomlBytes,
+}
+
+// stringyFormats marks the file extensions, keyed the same as parsers, whose
+// values are always raw strings with no native notion of numbers or
+// booleans (currently just dotenv), so Load knows to decode them with
+// decodeContext.stringyLeaves set.
+var stringyFormats = map[string]bool{}
+
+// Load loads the file into v, inferring the format from the file extension.
+// Supported extensions are .json, .yaml, .yml, .toml and .env.
+func Load(file string, v any, opts ...Option) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var opt options
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.env {
+		content = []byte(os.Expand(string(content), os.Getenv))
+	}
+
+	ext := strings.ToLower(path.Ext(file))
+	parser, ok := parsers[ext]
+	if !ok {
+		return fmt.Errorf("conf: unrecognized file type: %s", file)
+	}
+
+	data, err := parser(content)
+	if err != nil {
+		return err
+	}
+
+	return decodeWithContext(data, v, &decodeContext{flagSet: opt.flagSet, stringyLeaves: stringyFormats[ext]})
+}
+
+// LoadConfig is an alias of Load, kept for callers that were written
+// against the older name.
+func LoadConfig(file string, v any, opts ...Option) error {
+	return Load(file, v, opts...)
+}
+
+// MustLoad loads the file into v, panicking if it can't.
+func MustLoad(file string, v any, opts ...Option) {
+	if err := Load(file, v, opts...); err != nil {
+		panic(fmt.Errorf("config file %s, %w", file, err))
+	}
+}
+
+// LoadFromJsonBytes unmarshals content as JSON into v, matching fields
+// case-insensitively against their json tag or field name.
+func LoadFromJsonBytes(content []byte, v any) error {
+	data, err := parseJsonBytes(content)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalGeneric(data, v)
+}
+
+// LoadFromYamlBytes unmarshals content as YAML into v, matching fields
+// case-insensitively against their json tag or field name.
+func LoadFromYamlBytes(content []byte, v any) error {
+	data, err := parseYamlBytes(content)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalGeneric(data, v)
+}
+
+// LoadFromTomlBytes unmarshals content as TOML into v, matching fields
+// case-insensitively against their json tag or field name.
+func LoadFromTomlBytes(content []byte, v any) error {
+	data, err := parseTomlBytes(content)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalGeneric(data, v)
+}
+
+func parseJsonBytes(content []byte) (any, error) {
+	var data any
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func parseYamlBytes(content []byte) (any, error) {
+	var data any
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return normalizeYaml(data), nil
+}
+
+func parseTomlBytes(content []byte) (any, error) {
+	var data any
+	if err := toml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// LoadConfigFromJsonBytes is an alias of LoadFromJsonBytes.
+func LoadConfigFromJsonBytes(content []byte, v any) error {
+	return LoadFromJsonBytes(content, v)
+}
+
+// LoadConfigFromYamlBytes is an alias of LoadFromYamlBytes.
+func LoadConfigFromYamlBytes(content []byte, v any) error {
+	return LoadFromYamlBytes(content, v)
+}
+
+// FillDefault fills the zero-valued fields of v, which must be a non-nil
+// pointer to a struct, from their `default=` and `env=` tags. It's meant to
+// run against a freshly zero-valued struct: a field that carries a
+// `default=` tag but already holds a non-zero value is treated as a
+// conflict and returns an error.
+func FillDefault(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("conf: FillDefault requires a non-nil pointer")
+	}
+
+	return fillDefault(rv.Elem())
+}
+
+func fillDefault(rv reflect.Value) error {
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		fv := rv.Field(i)
+		tag := parseFieldTag(sf.Tag.Get(jsonTagName))
+
+		if fv.Kind() == reflect.Struct {
+			if err := fillDefault(fv); err != nil {
+				return err
+			}
+		}
+
+		if tag.hasDefault {
+			if !fv.IsZero() {
+				return fmt.Errorf("conf: field %q already has a value, default not applied", sf.Name)
+			}
+			if err := setFromString(fv, tag.defaultVal); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fv.IsZero() {
+			for _, envKey := range tag.envKeys {
+				if val, ok := os.LookupEnv(envKey); ok && val != "" {
+					if err := setFromString(fv, val); err != nil {
+						return err
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// toLowerCase normalizes a struct field name/tag so that keys can be
+// matched against config content case-insensitively.
+func toLowerCase(s string) string {
+	return strings.ToLower(s)
+}
+
+type dupKeyError struct {
+	key string
+}
+
+func newDupKeyError(key string) dupKeyError {
+	return dupKeyError{key: key}
+}
+
+func (e dupKeyError) Error() string {
+	return fmt.Sprintf("conf: duplicate key %q from overlapping fields", e.key)
+}
+
+type fieldTag struct {
+	name       string
+	optional   bool
+	hasDefault bool
+	defaultVal string
+	// envKeys holds one or more env var names in precedence order, e.g.
+	// `env=PRIMARY|FALLBACK|LEGACY` tries PRIMARY first.
+	envKeys  []string
+	flagName string
+}
+
+func parseFieldTag(raw string) fieldTag {
+	if raw == "" {
+		return fieldTag{}
+	}
+
+	parts := strings.Split(raw, ",")
+	tag := fieldTag{name: parts[0]}
+	for _, part := range parts[1:] {
+		switch {
+		case part == "optional":
+			tag.optional = true
+		case strings.HasPrefix(part, "optional="):
+			tag.optional = true
+		case strings.HasPrefix(part, "default="):
+			tag.hasDefault = true
+			tag.defaultVal = part[len("default="):]
+		case strings.HasPrefix(part, "env="):
+			tag.envKeys = strings.Split(part[len("env="):], "|")
+		case strings.HasPrefix(part, "flag="):
+			tag.flagName = part[len("flag="):]
+		}
+	}
+
+	return tag
+}
+
+type leafField struct {
+	value reflect.Value
+	tag   fieldTag
+	key   string
+}
+
+type structField struct {
+	value reflect.Value
+}
+
+// flagLookuper is the narrow view of a flag set that conf needs, so this
+// file doesn't have to import pflag directly; WithFlagSet adapts a
+// *pflag.FlagSet to it.
+type flagLookuper interface {
+	Lookup(name string) interface {
+		// String returns the flag's current value. WithFlagSet's adapter
+		// returns nil from Lookup for a flag never set on the command line,
+		// so a flag's registered default is never read through it.
+		String() string
+	}
+}
+
+// decodeContext carries the extra value sources (beyond the config file
+// itself) that a missing field can fall back to.
+type decodeContext struct {
+	flagSet flagLookuper
+
+	// stringyLeaves marks sources, like dotenv, whose values are always raw
+	// Go strings with no native notion of numbers or booleans. Leaves decoded
+	// under such a source go through setFromString's string-to-kind
+	// conversion instead of assignLeaf's encoding/json round-trip, so a value
+	// like "5432" reaches an int field as 5432 rather than failing to
+	// unmarshal the quoted JSON string literal assignLeaf would produce.
+	stringyLeaves bool
+}
+
+func unmarshalGeneric(data any, v any) error {
+	return decodeWithContext(data, v, nil)
+}
+
+func decodeWithContext(data any, v any, ctx *decodeContext) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("conf: v must be a non-nil pointer")
+	}
+
+	if err := validateStructType(rv.Elem().Type(), map[reflect.Type]bool{}); err != nil {
+		return err
+	}
+
+	return decodeValue(rv.Elem(), data, ctx)
+}
+
+// validateStructType walks every struct type reachable from t through
+// pointers, slices, arrays and maps, and reports a dupKeyError for any of
+// them whose own field set is ambiguous under anonymous-field promotion.
+// decodeStruct already catches these collisions when they occur between
+// sibling destinations at the same nesting level, but a struct type that's
+// only reachable through a slice/map/pointer field, or whose config data is
+// missing or shaped differently than expected, would otherwise never be
+// walked by decodeStruct at all. Validating the type graph up front, before
+// any data is considered, catches those too.
+func validateStructType(t reflect.Type, visited map[reflect.Type]bool) error {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return validateStructType(t.Elem(), visited)
+	case reflect.Struct:
+		if visited[t] {
+			return nil
+		}
+		visited[t] = true
+
+		zero := reflect.New(t).Elem()
+		if _, _, _, err := planStruct([]reflect.Value{zero}); err != nil {
+			return err
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			if err := validateStructType(t.Field(i).Type, visited); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeValue(rv reflect.Value, data any, ctx *decodeContext) error {
+	if data == nil {
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(rv.Elem(), data, ctx)
+	case reflect.Struct:
+		m, ok := toStringMap(data)
+		if !ok {
+			return fmt.Errorf("conf: expected an object for %s", rv.Type())
+		}
+		return decodeStruct([]reflect.Value{rv}, m, ctx)
+	case reflect.Slice:
+		list, ok := data.([]any)
+		if !ok {
+			return fmt.Errorf("conf: expected an array for %s", rv.Type())
+		}
+		out := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeValue(out.Index(i), item, ctx); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+		return nil
+	case reflect.Map:
+		m, ok := toStringMap(data)
+		if !ok {
+			return fmt.Errorf("conf: expected an object for %s", rv.Type())
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), len(m))
+		for k, val := range m {
+			ev := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(ev, val, ctx); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k), ev)
+		}
+		rv.Set(out)
+		return nil
+	default:
+		if s, ok := data.(string); ok && ctx != nil && ctx.stringyLeaves && rv.Kind() != reflect.String {
+			return setFromString(rv, s)
+		}
+		return assignLeaf(rv, data)
+	}
+}
+
+// decodeStruct populates dests, a set of struct values that were merged
+// together by anonymous-field promotion, from data. Fields promoted from
+// more than one embedded struct are recursed into together; a key that
+// resolves to more than one leaf, or to both a leaf and a struct, is
+// ambiguous and reported as a dupKeyError.
+func decodeStruct(dests []reflect.Value, data map[string]any, ctx *decodeContext) error {
+	leaves, structs, dotted, err := planStruct(dests)
+	if err != nil {
+		return err
+	}
+
+	lowerData := make(map[string]any, len(data))
+	for k, v := range data {
+		lowerData[toLowerCase(k)] = v
+	}
+
+	for key, fields := range leaves {
+		if raw, ok := lowerData[key]; ok {
+			if err := decodeValue(fields[0].value, raw, ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := applyMissingLeaf(fields[0], ctx); err != nil {
+			return err
+		}
+	}
+
+	for key, group := range structs {
+		raw, ok := lowerData[key]
+		if !ok {
+			continue
+		}
+		sub, ok := toStringMap(raw)
+		if !ok {
+			return fmt.Errorf("conf: expected an object for field %q", key)
+		}
+
+		values := make([]reflect.Value, len(group))
+		for i, sf := range group {
+			values[i] = sf.value
+		}
+		if err := decodeStruct(values, sub, ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, lf := range dotted {
+		raw, ok := lookupPath(data, strings.Split(lf.key, "."))
+		if ok {
+			if err := decodeValue(lf.value, raw, ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := applyMissingLeaf(lf, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func planStruct(dests []reflect.Value) (map[string][]leafField, map[string][]structField, []leafField, error) {
+	leaves := map[string][]leafField{}
+	structs := map[string][]structField{}
+	var dotted []leafField
+
+	queue := append([]reflect.Value(nil), dests...)
+	for len(queue) > 0 {
+		dv := queue[0]
+		queue = queue[1:]
+
+		for dv.Kind() == reflect.Ptr {
+			if dv.IsNil() {
+				dv.Set(reflect.New(dv.Type().Elem()))
+			}
+			dv = dv.Elem()
+		}
+		if dv.Kind() != reflect.Struct {
+			continue
+		}
+
+		rt := dv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			if sf.PkgPath != "" && !sf.Anonymous {
+				continue
+			}
+
+			tag := parseFieldTag(sf.Tag.Get(jsonTagName))
+			if tag.name == "-" {
+				continue
+			}
+
+			fv := dv.Field(i)
+			resolved := fv
+			for resolved.Kind() == reflect.Ptr {
+				if resolved.IsNil() {
+					if resolved.Type().Elem().Kind() != reflect.Struct {
+						break
+					}
+					resolved.Set(reflect.New(resolved.Type().Elem()))
+				}
+				resolved = resolved.Elem()
+			}
+
+			if sf.Anonymous && tag.name == "" && resolved.Kind() == reflect.Struct {
+				queue = append(queue, fv)
+				continue
+			}
+
+			name := tag.name
+			if name == "" {
+				name = sf.Name
+			}
+			key := toLowerCase(name)
+
+			if strings.Contains(key, ".") {
+				dotted = append(dotted, leafField{value: fv, tag: tag, key: key})
+				continue
+			}
+
+			if resolved.Kind() == reflect.Struct {
+				structs[key] = append(structs[key], structField{value: fv})
+			} else {
+				leaves[key] = append(leaves[key], leafField{value: fv, tag: tag, key: key})
+			}
+		}
+	}
+
+	for key := range leaves {
+		if _, ok := structs[key]; ok {
+			return nil, nil, nil, newDupKeyError(key)
+		}
+		if len(leaves[key]) > 1 {
+			return nil, nil, nil, newDupKeyError(key)
+		}
+	}
+
+	return leaves, structs, dotted, nil
+}
+
+// applyMissingLeaf fills a field absent from the config file from its other
+// sources, in precedence order: first non-empty env var, then bound flag,
+// then default=. A field that still has nothing is an error unless it's
+// marked optional.
+func applyMissingLeaf(lf leafField, ctx *decodeContext) error {
+	for _, envKey := range lf.tag.envKeys {
+		if val, ok := os.LookupEnv(envKey); ok && val != "" {
+			return setFromString(lf.value, val)
+		}
+	}
+
+	if ctx != nil && ctx.flagSet != nil && lf.tag.flagName != "" {
+		if f := ctx.flagSet.Lookup(lf.tag.flagName); f != nil {
+			return setFromString(lf.value, f.String())
+		}
+	}
+
+	if lf.tag.hasDefault {
+		return setFromString(lf.value, lf.tag.defaultVal)
+	}
+	if lf.tag.optional {
+		return nil
+	}
+
+	return fmt.Errorf("conf: field %q is required", lf.key)
+}
+
+func assignLeaf(rv reflect.Value, raw any) error {
+	if !rv.CanSet() {
+		return nil
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	ptr := reflect.New(rv.Type())
+	if err := json.Unmarshal(b, ptr.Interface()); err != nil {
+		return fmt.Errorf("conf: cannot assign value into field of type %s: %w", rv.Type(), err)
+	}
+	rv.Set(ptr.Elem())
+	return nil
+}
+
+func setFromString(rv reflect.Value, s string) error {
+	if !rv.CanSet() {
+		return nil
+	}
+
+	if rv.Kind() == reflect.String {
+		rv.SetString(s)
+		return nil
+	}
+
+	ptr := reflect.New(rv.Type())
+	if err := json.Unmarshal([]byte(s), ptr.Interface()); err != nil {
+		return fmt.Errorf("conf: cannot convert %q into field of type %s: %w", s, rv.Type(), err)
+	}
+	rv.Set(ptr.Elem())
+	return nil
+}
+
+func lookupPath(data any, segments []string) (any, bool) {
+	cur := data
+	for _, seg := range segments {
+		m, ok := toStringMap(cur)
+		if !ok {
+			return nil, false
+		}
+
+		lower := toLowerCase(seg)
+		found := false
+		for k, v := range m {
+			if toLowerCase(k) == lower {
+				cur = v
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+func toStringMap(data any) (map[string]any, bool) {
+	switch v := data.(type) {
+	case map[string]any:
+		return v, true
+	case map[interface{}]interface{}:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[fmt.Sprint(k)] = normalizeYaml(val)
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func normalizeYaml(v any) any {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]any, len(val))
+		for k, vv := range val {
+			out[fmt.Sprint(k)] = normalizeYaml(vv)
+		}
+		return out
+	case []interface{}:
+		out := make([]any, len(val))
+		for i, vv := range val {
+			out[i] = normalizeYaml(vv)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,108 @@
+package conf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// dotenvSeparator joins nested keys when flattening a dotenv file onto a
+// struct, e.g. DB_HOST maps onto the "db" struct's "host" field.
+const dotenvSeparator = "_"
+
+func init() {
+	loaders[".env"] = LoadFromDotenvBytes
+	parsers[".env"] = parseDotenvBytes
+	stringyFormats[".env"] = true
+}
+
+// LoadFromDotenvBytes unmarshals content formatted as a flat KEY=VALUE
+// dotenv file into v. Keys are lowercased and split on underscores to reach
+// nested struct fields, so DB_HOST populates a field reachable via "db" then
+// "host" (matching either a db.host struct path or a single field tagged
+// json:"db_host").
+func LoadFromDotenvBytes(content []byte, v any) error {
+	data, err := parseDotenvBytes(content)
+	if err != nil {
+		return err
+	}
+
+	return decodeWithContext(data, v, &decodeContext{stringyLeaves: true})
+}
+
+func parseDotenvBytes(content []byte) (any, error) {
+	flat, err := parseDotenv(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return nestDotenv(flat), nil
+}
+
+func parseDotenv(content []byte) (map[string]string, error) {
+	flat := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			return nil, fmt.Errorf("conf: invalid dotenv line: %s", line)
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		val := strings.TrimSpace(line[idx+1:])
+		val = unquoteDotenvValue(val)
+		flat[toLowerCase(key)] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+func unquoteDotenvValue(val string) string {
+	if len(val) >= 2 {
+		if (val[0] == '"' && val[len(val)-1] == '"') || (val[0] == '\'' && val[len(val)-1] == '\'') {
+			return val[1 : len(val)-1]
+		}
+	}
+	return val
+}
+
+// nestDotenv turns a flat KEY_CHILD=value map into nested
+// map[string]any{"key": map[string]any{"child": "value"}}, while also
+// keeping the original flat key so a struct field tagged with the literal
+// underscored name (e.g. json:"db_host") still matches directly.
+func nestDotenv(flat map[string]string) map[string]any {
+	out := make(map[string]any, len(flat))
+	for key, val := range flat {
+		out[key] = val
+
+		parts := strings.Split(key, dotenvSeparator)
+		if len(parts) < 2 {
+			continue
+		}
+
+		cur := out
+		for i, part := range parts[:len(parts)-1] {
+			next, ok := cur[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				cur[part] = next
+			}
+			cur = next
+			_ = i
+		}
+		cur[parts[len(parts)-1]] = val
+	}
+
+	return out
+}
@@ -0,0 +1,77 @@
+package conf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFromDotenvBytes(t *testing.T) {
+	input := []byte(`
+# comment
+DB_HOST=localhost
+DB_PORT=5432
+TITLE="hello world"
+`)
+
+	var val struct {
+		Db struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		} `json:"db"`
+		Title string `json:"title"`
+	}
+
+	assert.NoError(t, LoadFromDotenvBytes(input, &val))
+	assert.Equal(t, "localhost", val.Db.Host)
+	assert.Equal(t, 5432, val.Db.Port)
+	assert.Equal(t, "hello world", val.Title)
+}
+
+func TestLoadFromDotenvBytesFlatTag(t *testing.T) {
+	input := []byte(`DB_HOST=localhost`)
+
+	var val struct {
+		DbHost string `json:"db_host"`
+	}
+
+	assert.NoError(t, LoadFromDotenvBytes(input, &val))
+	assert.Equal(t, "localhost", val.DbHost)
+}
+
+func TestLoadDotenvViaLoadConfig(t *testing.T) {
+	tmpfile, err := createTempFile(".env", "TITLE=${FOO}\n")
+	assert.Nil(t, err)
+	defer os.Remove(tmpfile)
+
+	os.Setenv("FOO", "bar")
+	defer os.Unsetenv("FOO")
+
+	var val struct {
+		Title string `json:"title"`
+	}
+	assert.NoError(t, LoadConfig(tmpfile, &val, UseEnv()))
+	assert.Equal(t, "bar", val.Title)
+}
+
+func TestLoadDotenvViaLoadConfigNonStringField(t *testing.T) {
+	tmpfile, err := createTempFile(".env", "DB_PORT=5432\nDEBUG=true\n")
+	assert.Nil(t, err)
+	defer os.Remove(tmpfile)
+
+	var val struct {
+		Db struct {
+			Port int `json:"port"`
+		} `json:"db"`
+		Debug bool `json:"debug"`
+	}
+	assert.NoError(t, LoadConfig(tmpfile, &val))
+	assert.Equal(t, 5432, val.Db.Port)
+	assert.True(t, val.Debug)
+}
+
+func TestLoadFromDotenvBytesInvalidLine(t *testing.T) {
+	var val struct{}
+	assert.Error(t, LoadFromDotenvBytes([]byte("not-a-valid-line"), &val))
+}
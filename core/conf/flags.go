@@ -0,0 +1,29 @@
+package conf
+
+import "github.com/spf13/pflag"
+
+// WithFlagSet lets Load/MustLoad fall back to fs when a field tagged
+// `flag=name` is absent from both the config file and its `env=` sources.
+// Precedence is: config file value > first non-empty env var > flag value >
+// `default=`.
+func WithFlagSet(fs *pflag.FlagSet) Option {
+	return func(opt *options) {
+		opt.flagSet = pflagLookuper{fs}
+	}
+}
+
+type pflagLookuper struct {
+	fs *pflag.FlagSet
+}
+
+// Lookup only returns a value once the flag was actually supplied (f.Changed),
+// not merely registered with its zero-value default — otherwise an
+// `fs.String("name", "", "")` left unparsed would silently win over the
+// field's own `default=` tag instead of falling through to it.
+func (p pflagLookuper) Lookup(name string) interface{ String() string } {
+	f := p.fs.Lookup(name)
+	if f == nil || !f.Changed {
+		return nil
+	}
+	return f.Value
+}
@@ -0,0 +1,61 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnvFallbackOrder(t *testing.T) {
+	os.Unsetenv("CONF_TEST_PRIMARY")
+	os.Setenv("CONF_TEST_FALLBACK", "from-fallback")
+	defer os.Unsetenv("CONF_TEST_FALLBACK")
+
+	var val struct {
+		Name string `json:"name,env=CONF_TEST_PRIMARY|CONF_TEST_FALLBACK"`
+	}
+	assert.NoError(t, LoadFromJsonBytes([]byte(`{}`), &val))
+	assert.Equal(t, "from-fallback", val.Name)
+}
+
+func TestLoadWithFlagSetFallback(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf.json")
+	assert.NoError(t, os.WriteFile(filename, []byte(`{}`), 0o644))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("name", "", "")
+	assert.NoError(t, fs.Set("name", "from-flag"))
+
+	var val struct {
+		Name string `json:"name,flag=name"`
+	}
+	assert.NoError(t, Load(filename, &val, WithFlagSet(fs)))
+	assert.Equal(t, "from-flag", val.Name)
+}
+
+func TestLoadFlagUnsetFallsThroughToDefault(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf.json")
+	assert.NoError(t, os.WriteFile(filename, []byte(`{}`), 0o644))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("name", "", "")
+
+	var val struct {
+		Name string `json:"name,flag=name,default=fallback"`
+	}
+	assert.NoError(t, Load(filename, &val, WithFlagSet(fs)))
+	assert.Equal(t, "fallback", val.Name)
+}
+
+func TestLoadMissingRequiredField(t *testing.T) {
+	var val struct {
+		Name string `json:"name"`
+	}
+	err := LoadFromJsonBytes([]byte(`{}`), &val)
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// RemoteProvider is the pluggable source LoadFromRemote reads from. Get
+// returns the raw payload for key plus a format hint ("json", "yaml" or
+// "toml") so the existing decoders can be reused. Watch, if the provider
+// supports change notifications, streams the same (payload, format) pair
+// every time key changes; providers that can't watch may return a nil
+// channel. done is closed when the caller stops watching (LoadFromRemote's
+// returned stop func); Watch must stop its background work and close the
+// returned channel once done fires instead of leaking a goroutine blocked
+// on a send nobody will ever read.
+type RemoteProvider interface {
+	Get(key string) (content []byte, format string, err error)
+	Watch(key string, done <-chan struct{}) (<-chan RemoteUpdate, error)
+}
+
+// RemoteUpdate is one change notification from a RemoteProvider's Watch
+// channel.
+type RemoteUpdate struct {
+	Content []byte
+	Format  string
+	Err     error
+}
+
+// LoadFromRemote reads key from provider, decodes it into v using the
+// format the provider reports, and, if the provider supports watching,
+// keeps v updated for as long as the returned stop func isn't called. It's
+// the remote-source counterpart of Load/Watch: it picks its decoder from the
+// same parsers map as Load and, like Watch, decodes into a fresh value that
+// is swapped into v only once decoding succeeded.
+//
+// onChange is invoked, mirroring Watch, once per background update after v
+// has been swapped (or, if the provider's watch stream errored or the update
+// failed to decode, with that error). It is never called for the initial
+// load, whose error is returned directly. A caller that reads v from a
+// goroutine other than onChange must synchronize with it itself; onChange
+// firing is the signal that the swap already happened.
+func LoadFromRemote(provider RemoteProvider, key string, v any, onChange func(error), opts ...Option) (stop func(), err error) {
+	content, format, err := provider.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var opt options
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	if err := decodeRemote(content, format, v, &opt); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var stopOnce sync.Once
+
+	updates, err := provider.Watch(key, done)
+	if err != nil {
+		return nil, err
+	}
+	if updates == nil {
+		return func() {}, nil
+	}
+
+	go func() {
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if update.Err != nil {
+					onChange(update.Err)
+					continue
+				}
+				onChange(decodeRemote(update.Content, update.Format, v, &opt))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { stopOnce.Do(func() { close(done) }) }, nil
+}
+
+// decodeRemote decodes content into a fresh value of v's type before
+// swapping it into v, the same atomic-swap-on-success behavior Watch uses
+// for file-based sources.
+func decodeRemote(content []byte, format string, v any, opt *options) error {
+	if opt.env {
+		content = []byte(os.Expand(string(content), os.Getenv))
+	}
+
+	parser, ok := parsers["."+format]
+	if !ok {
+		return fmt.Errorf("conf: unrecognized remote format: %s", format)
+	}
+
+	data, err := parser(content)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("conf: v must be a non-nil pointer")
+	}
+
+	fresh := reflect.New(rv.Type().Elem())
+	if err := decodeWithContext(data, fresh.Interface(), &decodeContext{flagSet: opt.flagSet}); err != nil {
+		return err
+	}
+
+	rv.Elem().Set(fresh.Elem())
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package consul provides a conf.RemoteProvider backed by Consul's KV
+// store, polling with blocking queries for change notifications the way
+// Consul-aware services already watch other keys.
+package consul
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// watchRetryDelay is how long Watch backs off after a blocking query fails
+// before retrying, so a transient error (a leader election, a dropped
+// connection) doesn't spin a tight retry loop. A var, not a const, so tests
+// can shorten it.
+var watchRetryDelay = time.Second
+
+// kvStore is the subset of *api.KV that Provider needs, narrowed so tests
+// can fake it instead of standing up a real Consul agent.
+type kvStore interface {
+	Get(key string, q *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error)
+}
+
+// Provider reads a single Consul KV key and streams subsequent values using
+// blocking queries keyed off the index Consul returns with each read.
+type Provider struct {
+	kv     kvStore
+	format string
+}
+
+// New builds a Provider from an already-configured Consul client.
+func New(client *api.Client, format string) *Provider {
+	return &Provider{kv: client.KV(), format: format}
+}
+
+// Get implements conf.RemoteProvider.
+func (p *Provider) Get(key string) ([]byte, string, error) {
+	pair, _, err := p.kv.Get(key, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if pair == nil {
+		return nil, "", fmt.Errorf("consul: key not found: %s", key)
+	}
+
+	return pair.Value, p.format, nil
+}
+
+// Watch implements conf.RemoteProvider using long-polling blocking queries:
+// each call waits for Consul's KV index to advance past the last index seen
+// before sending an update. The first query has no index yet, so it returns
+// at once and the key's current value is sent as the first update; after
+// that the channel only wakes up when the index advances. A
+// failed query is retried after watchRetryDelay rather than ending the
+// watch, since a blip (a leader election, a dropped connection) shouldn't
+// permanently stop reloading. The loop, and any pending retry sleep, stop as
+// soon as done fires instead of blocking forever on a send nobody reads.
+func (p *Provider) Watch(key string, done <-chan struct{}) (<-chan conf.RemoteUpdate, error) {
+	updates := make(chan conf.RemoteUpdate)
+
+	go func() {
+		defer close(updates)
+
+		var lastIndex uint64
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			pair, meta, err := p.kv.Get(key, &api.QueryOptions{WaitIndex: lastIndex})
+			if err != nil {
+				select {
+				case updates <- conf.RemoteUpdate{Err: err}:
+				case <-done:
+					return
+				}
+				select {
+				case <-time.After(watchRetryDelay):
+				case <-done:
+					return
+				}
+				continue
+			}
+			if meta.LastIndex == lastIndex {
+				continue
+			}
+			lastIndex = meta.LastIndex
+
+			if pair == nil {
+				continue
+			}
+			select {
+			case updates <- conf.RemoteUpdate{Content: pair.Value, Format: p.format}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,118 @@
+package consul
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKV struct {
+	pair  *api.KVPair
+	index uint64
+	err   error
+	calls int
+}
+
+func (f *fakeKV) Get(key string, q *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return f.pair, &api.QueryMeta{LastIndex: f.index}, nil
+}
+
+func TestProviderGet(t *testing.T) {
+	kv := &fakeKV{pair: &api.KVPair{Value: []byte("content")}}
+	p := &Provider{kv: kv, format: "json"}
+
+	content, format, err := p.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", format)
+	assert.Equal(t, []byte("content"), content)
+}
+
+func TestProviderGetNotFound(t *testing.T) {
+	p := &Provider{kv: &fakeKV{}, format: "json"}
+
+	_, _, err := p.Get("key")
+	assert.Error(t, err)
+}
+
+// sequencedKV serves a fixed sequence of responses, one per call, so Watch's
+// retry loop can be observed advancing through an error and then a value.
+type sequencedKV struct {
+	pairs   []*api.KVPair
+	indexes []uint64
+	errs    []error
+	i       int
+}
+
+func (s *sequencedKV) Get(key string, q *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error) {
+	i := s.i
+	if i >= len(s.pairs) {
+		i = len(s.pairs) - 1
+	}
+	s.i++
+
+	if s.errs[i] != nil {
+		return nil, nil, s.errs[i]
+	}
+	return s.pairs[i], &api.QueryMeta{LastIndex: s.indexes[i]}, nil
+}
+
+func TestProviderWatchRetriesOnError(t *testing.T) {
+	origDelay := watchRetryDelay
+	watchRetryDelay = time.Millisecond
+	defer func() { watchRetryDelay = origDelay }()
+
+	kv := &sequencedKV{
+		pairs:   []*api.KVPair{nil, {Value: []byte("v1")}},
+		indexes: []uint64{0, 1},
+		errs:    []error{errors.New("blip"), nil},
+	}
+	p := &Provider{kv: kv, format: "json"}
+
+	updates, err := p.Watch("key", nil)
+	assert.NoError(t, err)
+
+	first := <-updates
+	assert.Error(t, first.Err)
+
+	second := <-updates
+	assert.NoError(t, second.Err)
+	assert.Equal(t, []byte("v1"), second.Content)
+}
+
+// blockingKV serves an always-changing index so Watch's retry loop never
+// runs dry, letting TestProviderWatchStopsOnDone verify it still exits
+// instead of looping or blocking forever once nothing reads updates.
+type blockingKV struct {
+	index uint64
+}
+
+func (b *blockingKV) Get(key string, q *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error) {
+	b.index++
+	return &api.KVPair{Value: []byte("v")}, &api.QueryMeta{LastIndex: b.index}, nil
+}
+
+func TestProviderWatchStopsOnDone(t *testing.T) {
+	p := &Provider{kv: &blockingKV{}, format: "json"}
+
+	done := make(chan struct{})
+	updates, err := p.Watch("key", done)
+	assert.NoError(t, err)
+
+	<-updates // let the goroutine block trying to send the next update
+	time.Sleep(50 * time.Millisecond)
+	close(done)
+
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Watch goroutine leaked past done")
+	}
+}
@@ -0,0 +1,93 @@
+// Package etcd provides a conf.RemoteProvider backed by etcd v3, so
+// services can load and watch their config from a shared etcd cluster the
+// same way they'd load it from a local file.
+package etcd
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// etcdClient is the subset of *clientv3.Client that Provider needs, narrowed
+// so tests can fake it instead of standing up a real etcd cluster.
+type etcdClient interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+// Provider reads a single etcd key and streams its subsequent revisions.
+// Format is the hint ("json", "yaml" or "toml") passed back to the caller's
+// decoders, since etcd stores opaque bytes with no notion of content type.
+type Provider struct {
+	client etcdClient
+	format string
+}
+
+// New builds a Provider from an already-configured etcd client. The caller
+// owns the client's lifecycle and should Close it after the provider is no
+// longer in use.
+func New(client *clientv3.Client, format string) *Provider {
+	return &Provider{client: client, format: format}
+}
+
+// Get implements conf.RemoteProvider.
+func (p *Provider) Get(key string) ([]byte, string, error) {
+	resp, err := p.client.Get(context.Background(), key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, "", fmt.Errorf("etcd: key not found: %s", key)
+	}
+
+	return resp.Kvs[0].Value, p.format, nil
+}
+
+// Watch implements conf.RemoteProvider, forwarding every PUT on key as a
+// conf.RemoteUpdate. The channel closes, and the underlying etcd watch is
+// canceled, when done fires or the watch ends on its own.
+func (p *Provider) Watch(key string, done <-chan struct{}) (<-chan conf.RemoteUpdate, error) {
+	updates := make(chan conf.RemoteUpdate)
+	ctx, cancel := context.WithCancel(context.Background())
+	watchCh := p.client.Watch(ctx, key)
+
+	go func() {
+		defer cancel()
+		defer close(updates)
+
+		for {
+			select {
+			case resp, ok := <-watchCh:
+				if !ok {
+					return
+				}
+				if err := resp.Err(); err != nil {
+					select {
+					case updates <- conf.RemoteUpdate{Err: err}:
+					case <-done:
+						return
+					}
+					continue
+				}
+				for _, event := range resp.Events {
+					if event.Type != clientv3.EventTypePut {
+						continue
+					}
+					select {
+					case updates <- conf.RemoteUpdate{Content: event.Kv.Value, Format: p.format}:
+					case <-done:
+						return
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,104 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mvccpb "go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type fakeEtcdClient struct {
+	getResp *clientv3.GetResponse
+	getErr  error
+	watchCh chan clientv3.WatchResponse
+}
+
+func (f *fakeEtcdClient) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return f.getResp, f.getErr
+}
+
+func (f *fakeEtcdClient) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	return f.watchCh
+}
+
+func TestProviderGet(t *testing.T) {
+	p := &Provider{
+		client: &fakeEtcdClient{
+			getResp: &clientv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{Value: []byte("content")}},
+			},
+		},
+		format: "yaml",
+	}
+
+	content, format, err := p.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", format)
+	assert.Equal(t, []byte("content"), content)
+}
+
+func TestProviderGetNotFound(t *testing.T) {
+	p := &Provider{client: &fakeEtcdClient{getResp: &clientv3.GetResponse{}}, format: "yaml"}
+
+	_, _, err := p.Get("key")
+	assert.Error(t, err)
+}
+
+func TestProviderGetError(t *testing.T) {
+	p := &Provider{client: &fakeEtcdClient{getErr: errors.New("boom")}, format: "yaml"}
+
+	_, _, err := p.Get("key")
+	assert.Error(t, err)
+}
+
+func TestProviderWatchForwardsPuts(t *testing.T) {
+	watchCh := make(chan clientv3.WatchResponse, 1)
+	p := &Provider{client: &fakeEtcdClient{watchCh: watchCh}, format: "yaml"}
+
+	updates, err := p.Watch("key", nil)
+	assert.NoError(t, err)
+
+	watchCh <- clientv3.WatchResponse{
+		Events: []*clientv3.Event{
+			{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Value: []byte("v1")}},
+			{Type: clientv3.EventTypeDelete, Kv: &mvccpb.KeyValue{Value: []byte("ignored")}},
+		},
+	}
+	update := <-updates
+	assert.NoError(t, update.Err)
+	assert.Equal(t, []byte("v1"), update.Content)
+
+	close(watchCh)
+	_, ok := <-updates
+	assert.False(t, ok)
+}
+
+func TestProviderWatchStopsOnDone(t *testing.T) {
+	watchCh := make(chan clientv3.WatchResponse)
+	p := &Provider{client: &fakeEtcdClient{watchCh: watchCh}, format: "yaml"}
+
+	done := make(chan struct{})
+	updates, err := p.Watch("key", done)
+	assert.NoError(t, err)
+
+	// Queue a put that nothing reads, so the goroutine is blocked on the
+	// channel send when done fires.
+	watchCh <- clientv3.WatchResponse{
+		Events: []*clientv3.Event{
+			{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Value: []byte("v1")}},
+		},
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(done)
+
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Watch goroutine leaked past done")
+	}
+}
@@ -0,0 +1,106 @@
+// Package k8s provides a conf.RemoteProvider over a projected ConfigMap
+// volume. Kubernetes updates a mounted ConfigMap by writing the new files
+// into a timestamped directory and atomically repointing the volume's
+// "..data" symlink at it, so a plain fsnotify watch on the individual file
+// never fires; this package watches the symlink instead.
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// dataSymlink is the name Kubernetes gives the symlink it repoints on
+// every ConfigMap update; see the "atomic writer" implementation in
+// k8s.io/kubernetes/pkg/volume/util/atomic_writer.go.
+const dataSymlink = "..data"
+
+// Provider reads key as a file inside a ConfigMap volume mount at dir.
+type Provider struct {
+	dir    string
+	format string
+}
+
+// New builds a Provider reading files out of the ConfigMap volume mounted
+// at dir.
+func New(dir, format string) *Provider {
+	return &Provider{dir: dir, format: format}
+}
+
+// Get implements conf.RemoteProvider.
+func (p *Provider) Get(key string) ([]byte, string, error) {
+	content, err := os.ReadFile(filepath.Join(p.dir, key))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return content, p.format, nil
+}
+
+// Watch implements conf.RemoteProvider by watching the mount directory
+// itself for the "..data" symlink being repointed, rather than watching
+// the file, since the file's own inode never changes. The fsnotify watcher
+// is closed, and the goroutine stopped, as soon as done fires.
+func (p *Provider) Watch(key string, done <-chan struct{}) (<-chan conf.RemoteUpdate, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(p.dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	updates := make(chan conf.RemoteUpdate)
+	go func() {
+		defer close(updates)
+		defer watcher.Close()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != dataSymlink {
+					continue
+				}
+				if event.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+					continue
+				}
+
+				content, _, err := p.Get(key)
+				if err != nil {
+					select {
+					case updates <- conf.RemoteUpdate{Err: err}:
+					case <-done:
+						return
+					}
+					continue
+				}
+				select {
+				case updates <- conf.RemoteUpdate{Content: content, Format: p.format}:
+				case <-done:
+					return
+				}
+			case watchErr, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				select {
+				case updates <- conf.RemoteUpdate{Err: watchErr}:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
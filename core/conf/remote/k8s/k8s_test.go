@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfigMapVolume lays out a projected ConfigMap volume the way
+// Kubernetes' atomic writer does: the real file lives in a versioned
+// directory, and "..data" is a symlink pointing at it.
+func writeConfigMapVolume(t *testing.T, dir, version, key, content string) {
+	t.Helper()
+
+	versionDir := filepath.Join(dir, version)
+	assert.NoError(t, os.Mkdir(versionDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(versionDir, key), []byte(content), 0o644))
+
+	symlink := filepath.Join(dir, dataSymlink)
+	_ = os.Remove(symlink)
+	assert.NoError(t, os.Symlink(versionDir, symlink))
+
+	keyLink := filepath.Join(dir, key)
+	_ = os.Remove(keyLink)
+	assert.NoError(t, os.Symlink(filepath.Join(dataSymlink, key), keyLink))
+}
+
+func TestProviderGet(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMapVolume(t, dir, "v1", "config.json", `{"a":"foo"}`)
+
+	p := New(dir, "json")
+	content, format, err := p.Get("config.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", format)
+	assert.Equal(t, `{"a":"foo"}`, string(content))
+}
+
+func TestProviderGetMissing(t *testing.T) {
+	p := New(t.TempDir(), "json")
+
+	_, _, err := p.Get("missing.json")
+	assert.Error(t, err)
+}
+
+func TestProviderWatchFollowsDataSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMapVolume(t, dir, "v1", "config.json", `{"a":"foo"}`)
+
+	p := New(dir, "json")
+	updates, err := p.Watch("config.json", nil)
+	assert.NoError(t, err)
+
+	writeConfigMapVolume(t, dir, "v2", "config.json", `{"a":"bar"}`)
+
+	select {
+	case update := <-updates:
+		assert.NoError(t, update.Err)
+		assert.Equal(t, `{"a":"bar"}`, string(update.Content))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ConfigMap update")
+	}
+}
+
+func TestProviderWatchStopsOnDone(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMapVolume(t, dir, "v1", "config.json", `{"a":"foo"}`)
+
+	p := New(dir, "json")
+	done := make(chan struct{})
+	updates, err := p.Watch("config.json", done)
+	assert.NoError(t, err)
+
+	// Swap the symlink but never read the resulting update, so the
+	// goroutine is left blocked on the channel send when done fires.
+	writeConfigMapVolume(t, dir, "v2", "config.json", `{"a":"bar"}`)
+	time.Sleep(50 * time.Millisecond)
+	close(done)
+
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch goroutine leaked past done")
+	}
+}
@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	content []byte
+	format  string
+	updates chan RemoteUpdate
+	done    <-chan struct{}
+}
+
+func (f *fakeProvider) Get(string) ([]byte, string, error) {
+	return f.content, f.format, nil
+}
+
+func (f *fakeProvider) Watch(_ string, done <-chan struct{}) (<-chan RemoteUpdate, error) {
+	f.done = done
+	return f.updates, nil
+}
+
+func TestLoadFromRemote(t *testing.T) {
+	provider := &fakeProvider{
+		content: []byte(`{"a":"foo"}`),
+		format:  "json",
+		updates: make(chan RemoteUpdate, 1),
+	}
+
+	var val struct {
+		A string `json:"a"`
+	}
+	changed := make(chan error, 1)
+	stop, err := LoadFromRemote(provider, "key", &val, func(err error) {
+		changed <- err
+	})
+	assert.NoError(t, err)
+	defer stop()
+	assert.Equal(t, "foo", val.A)
+
+	provider.updates <- RemoteUpdate{Content: []byte(`{"a":"bar"}`), Format: "json"}
+
+	select {
+	case err := <-changed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for remote reload")
+	}
+	assert.Equal(t, "bar", val.A)
+}
+
+func TestLoadFromRemoteGetError(t *testing.T) {
+	provider := &errorProvider{err: errors.New("boom")}
+
+	var val struct{}
+	_, err := LoadFromRemote(provider, "key", &val, nil)
+	assert.Error(t, err)
+}
+
+func TestLoadFromRemoteStopSignalsProvider(t *testing.T) {
+	provider := &fakeProvider{
+		content: []byte(`{"a":"foo"}`),
+		format:  "json",
+		updates: make(chan RemoteUpdate),
+	}
+
+	var val struct {
+		A string `json:"a"`
+	}
+	stop, err := LoadFromRemote(provider, "key", &val, func(error) {})
+	assert.NoError(t, err)
+
+	stop()
+
+	select {
+	case <-provider.done:
+		// provider.Watch's done channel was closed, so a real provider's
+		// background goroutine would unblock and exit too.
+	case <-time.After(time.Second):
+		t.Fatal("stop() never signaled the provider's Watch to stop")
+	}
+}
+
+type errorProvider struct {
+	err error
+}
+
+func (p *errorProvider) Get(string) ([]byte, string, error) {
+	return nil, "", p.err
+}
+
+func (p *errorProvider) Watch(string, <-chan struct{}) (<-chan RemoteUpdate, error) {
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces the burst of events most editors (and os.Rename-
+// based atomic writers) generate for a single logical save into one reload.
+const watchDebounce = 100 * time.Millisecond
+
+// Watch observes filename and, on every change, reloads it into v under a
+// mutex and invokes onChange with the result. The initial content is not
+// loaded by Watch; call Load/MustLoad once before watching. It returns a
+// stop func that closes the underlying watcher, and mirrors editor
+// rename-replace saves (write to a temp file, then rename over the
+// original) by re-adding the watch whenever the file is removed or renamed
+// out from under it.
+func Watch(filename string, v any, onChange func(error)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	dir := filepath.Dir(abs)
+
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		timer   *time.Timer
+		done    = make(chan struct{})
+		stopped sync.Once
+	)
+
+	reload := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		onChange(reloadInto(abs, v))
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != abs {
+					continue
+				}
+				if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+					// Editors often save by writing a new inode then
+					// renaming it over filename; re-add the watch so we
+					// keep seeing events for the replacement file.
+					_ = watcher.Add(dir)
+				}
+
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(watchDebounce, reload)
+			case watchErr, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onChange(watchErr)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop = func() {
+		stopped.Do(func() {
+			close(done)
+			watcher.Close()
+		})
+	}
+
+	return stop, nil
+}
+
+// MustWatch is like Watch, but panics if the watcher can't be established.
+func MustWatch(filename string, v any, onChange func(error)) (stop func()) {
+	stop, err := Watch(filename, v, onChange)
+	if err != nil {
+		panic(fmt.Errorf("config file %s, %w", filename, err))
+	}
+	return stop
+}
+
+// reloadInto decodes filename into a fresh value and only then swaps it
+// into v, so a malformed save never leaves v half-updated.
+func reloadInto(filename string, v any) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	loader, ok := loaders[strings.ToLower(path.Ext(filename))]
+	if !ok {
+		return fmt.Errorf("conf: unrecognized file type: %s", filename)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("conf: v must be a non-nil pointer")
+	}
+
+	fresh := reflect.New(rv.Type().Elem())
+	if err := loader(content, fresh.Interface()); err != nil {
+		return err
+	}
+
+	rv.Elem().Set(fresh.Elem())
+	return nil
+}
@@ -0,0 +1,59 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf.json")
+	assert.NoError(t, os.WriteFile(filename, []byte(`{"a":"foo"}`), 0o644))
+
+	var val struct {
+		A string `json:"a"`
+	}
+	assert.NoError(t, Load(filename, &val))
+
+	changed := make(chan error, 1)
+	stop, err := Watch(filename, &val, func(err error) {
+		changed <- err
+	})
+	assert.NoError(t, err)
+	defer stop()
+
+	assert.NoError(t, os.WriteFile(filename, []byte(`{"a":"bar"}`), 0o644))
+
+	select {
+	case err := <-changed:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+	assert.Equal(t, "bar", val.A)
+}
+
+func TestWatchUnrecognizedFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf")
+	assert.NoError(t, os.WriteFile(filename, []byte(`a`), 0o644))
+
+	var val struct{}
+	changed := make(chan error, 1)
+	stop, err := Watch(filename, &val, func(err error) { changed <- err })
+	assert.NoError(t, err)
+	defer stop()
+
+	assert.NoError(t, os.WriteFile(filename, []byte(`b`), 0o644))
+
+	select {
+	case err := <-changed:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+}
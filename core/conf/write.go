@@ -0,0 +1,222 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrFileExists is returned by WriteConfig when WriteSafe is set and the
+// destination file already exists.
+var ErrFileExists = errors.New("conf: destination file already exists")
+
+type writeOptions struct {
+	safe bool
+}
+
+// WriteOption customizes how WriteConfig persists a value.
+type WriteOption func(opt *writeOptions)
+
+// WriteSafe makes WriteConfig fail with ErrFileExists instead of silently
+// overwriting an existing file.
+func WriteSafe() WriteOption {
+	return func(opt *writeOptions) {
+		opt.safe = true
+	}
+}
+
+var marshalers = map[string]func(any) ([]byte, error){
+	".json": MarshalToJson,
+	".yaml": MarshalToYaml,
+	".yml":  MarshalToYaml,
+	".toml": MarshalToToml,
+}
+
+// WriteConfig marshals v and writes it to filename, inferring the format
+// from the file extension the same way Load does. It's the write-side
+// counterpart of Load/LoadConfig.
+func WriteConfig(filename string, v any, opts ...WriteOption) error {
+	var opt writeOptions
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	if opt.safe {
+		if _, err := os.Stat(filename); err == nil {
+			return ErrFileExists
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	marshal, ok := marshalers[strings.ToLower(path.Ext(filename))]
+	if !ok {
+		return fmt.Errorf("conf: unrecognized file type: %s", filename)
+	}
+
+	content, err := marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, content, 0o644)
+}
+
+// WriteConfigTo marshals v in the given format ("json", "yaml", "yml" or
+// "toml") and writes the result to w.
+func WriteConfigTo(w io.Writer, format string, v any) error {
+	marshal, ok := marshalers["."+strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("conf: unrecognized format: %s", format)
+	}
+
+	content, err := marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(content)
+	return err
+}
+
+// MarshalToJson marshals v into JSON, using the field names Load matches
+// against and dropping conf-only tag metadata such as optional/default/env.
+func MarshalToJson(v any) ([]byte, error) {
+	tree, err := marshalValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(tree, "", "  ")
+}
+
+// MarshalToYaml marshals v into YAML. See MarshalToJson for field naming
+// rules.
+func MarshalToYaml(v any) ([]byte, error) {
+	tree, err := marshalValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(tree)
+}
+
+// MarshalToToml marshals v into TOML. See MarshalToJson for field naming
+// rules.
+func MarshalToToml(v any) ([]byte, error) {
+	tree, err := marshalValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tree); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// marshalValue turns rv into a tree of plain maps/slices/scalars suitable
+// for any of the three encoders, applying the same field-naming rules that
+// decodeStruct uses to read config back in: the declared json tag name (or
+// the field name), with optional/default/env metadata stripped, and values
+// from anonymous fields promoted into their parent.
+func marshalValue(rv reflect.Value) (any, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any)
+		if err := marshalStructFields(rv, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := marshalValue(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = item
+		}
+		return out, nil
+	case reflect.Map:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			item, err := marshalValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(iter.Key().Interface())] = item
+		}
+		return out, nil
+	default:
+		return rv.Interface(), nil
+	}
+}
+
+func marshalStructFields(rv reflect.Value, out map[string]any) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		tag := parseFieldTag(sf.Tag.Get(jsonTagName))
+		if tag.name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && tag.name == "" {
+			resolved := fv
+			for resolved.Kind() == reflect.Ptr && !resolved.IsNil() {
+				resolved = resolved.Elem()
+			}
+			if resolved.Kind() == reflect.Struct {
+				if err := marshalStructFields(resolved, out); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		name := tag.name
+		if name == "" {
+			name = sf.Name
+		}
+		if _, exists := out[name]; exists {
+			return newDupKeyError(name)
+		}
+
+		value, err := marshalValue(fv)
+		if err != nil {
+			return err
+		}
+		out[name] = value
+	}
+
+	return nil
+}
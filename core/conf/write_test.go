@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteConfigRoundTrip(t *testing.T) {
+	type Val struct {
+		A string `json:"a"`
+		B int    `json:"b"`
+	}
+
+	tests := []string{".json", ".yaml", ".yml", ".toml"}
+	for _, ext := range tests {
+		ext := ext
+		t.Run(ext, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "conf"+ext)
+
+			in := Val{A: "foo", B: 1}
+			assert.NoError(t, WriteConfig(filename, &in))
+
+			var out Val
+			assert.NoError(t, Load(filename, &out))
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestWriteConfigSafe(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "conf.json")
+
+	type Val struct {
+		A string `json:"a"`
+	}
+
+	assert.NoError(t, os.WriteFile(filename, []byte(`{"a":"foo"}`), 0o644))
+	err := WriteConfig(filename, &Val{A: "bar"}, WriteSafe())
+	assert.ErrorIs(t, err, ErrFileExists)
+}
+
+func TestWriteConfigStripsMetadata(t *testing.T) {
+	type Val struct {
+		A string `json:"a,optional"`
+		B string `json:"b,default=bar"`
+		C string `json:"c,env=WRITE_CONFIG_TEST_C"`
+	}
+
+	content, err := MarshalToJson(&Val{A: "foo", B: "bar", C: "baz"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"a"`)
+	assert.NotContains(t, string(content), "optional")
+	assert.NotContains(t, string(content), "default=")
+}
+
+func TestMarshalToJsonDuplicatePromotedKey(t *testing.T) {
+	type Inner1 struct {
+		X string
+	}
+	type Inner2 struct {
+		X string
+	}
+	type Outer struct {
+		Inner1
+		Inner2
+	}
+
+	_, err := MarshalToJson(&Outer{Inner1{X: "a"}, Inner2{X: "b"}})
+	assert.Error(t, err)
+	var dupErr dupKeyError
+	assert.ErrorAs(t, err, &dupErr)
+}
+
+func TestWriteConfigUnrecognizedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteConfigTo(&buf, "ini", struct{}{})
+	assert.Error(t, err)
+}